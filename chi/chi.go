@@ -0,0 +1,18 @@
+// Package chi wires masker into github.com/go-chi/chi handlers, which work
+// directly against http.ResponseWriter rather than a framework context.
+package chi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/surendratiwari3/masker"
+)
+
+// JSON masks v, then writes it as a JSON response.
+func JSON(w http.ResponseWriter, code int, v interface{}) error {
+	masker.Mask(v)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(v)
+}
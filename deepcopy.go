@@ -0,0 +1,93 @@
+package masker
+
+import "reflect"
+
+// deepCopyValue returns an independent copy of rv: new maps (via
+// reflect.MakeMapWithSize), new slices (via reflect.MakeSlice), and freshly
+// allocated pointers, so that later in-place masking of the copy can never
+// touch the original's backing data. memo remembers pointers/maps/slices
+// already copied (keyed by their reflect.Value.Pointer(), i.e. the
+// underlying address) so cyclic graphs terminate and shared references stay
+// shared in the copy the way they were in the original.
+//
+// Unexported struct fields are left at their zero value - like go-mask,
+// they can't be read back out through reflect.Value.Set anyway.
+func deepCopyValue(rv reflect.Value, memo map[uintptr]reflect.Value) reflect.Value {
+	if !rv.IsValid() {
+		return rv
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		addr := rv.Pointer()
+		if existing, ok := memo[addr]; ok {
+			return existing
+		}
+		copyPtr := reflect.New(rv.Type().Elem())
+		memo[addr] = copyPtr
+		copyPtr.Elem().Set(deepCopyValue(rv.Elem(), memo))
+		return copyPtr
+
+	case reflect.Struct:
+		copyStruct := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Type().Field(i).PkgPath != "" {
+				continue // unexported, not Set-able - leave zero
+			}
+			copyStruct.Field(i).Set(deepCopyValue(rv.Field(i), memo))
+		}
+		return copyStruct
+
+	case reflect.Slice:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		addr := rv.Pointer()
+		if existing, ok := memo[addr]; ok {
+			return existing
+		}
+		copySlice := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		memo[addr] = copySlice
+		for i := 0; i < rv.Len(); i++ {
+			copySlice.Index(i).Set(deepCopyValue(rv.Index(i), memo))
+		}
+		return copySlice
+
+	case reflect.Array:
+		copyArray := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			copyArray.Index(i).Set(deepCopyValue(rv.Index(i), memo))
+		}
+		return copyArray
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		addr := rv.Pointer()
+		if existing, ok := memo[addr]; ok {
+			return existing
+		}
+		copyMap := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		memo[addr] = copyMap
+		for _, key := range rv.MapKeys() {
+			copyMap.SetMapIndex(deepCopyValue(key, memo), deepCopyValue(rv.MapIndex(key), memo))
+		}
+		return copyMap
+
+	case reflect.Interface:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		copyIface := reflect.New(rv.Type()).Elem()
+		copyIface.Set(deepCopyValue(rv.Elem(), memo))
+		return copyIface
+
+	default:
+		// Basic kinds (string, int, bool, ...) are already copied by value.
+		return rv
+	}
+}
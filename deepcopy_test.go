@@ -0,0 +1,34 @@
+package masker
+
+import "testing"
+
+type cyclicNode struct {
+	Name string `mask:"strategy:full"`
+	Next *cyclicNode
+}
+
+func TestMaskCopyHandlesCycles(t *testing.T) {
+	a := &cyclicNode{Name: "a"}
+	b := &cyclicNode{Name: "b"}
+	a.Next = b
+	b.Next = a
+
+	result := MaskCopy(a, nil)
+	copied, ok := result.(*cyclicNode)
+	if !ok {
+		t.Fatalf("expected *cyclicNode, got %T", result)
+	}
+
+	if copied == a {
+		t.Fatalf("expected a deep copy, got the original pointer back")
+	}
+	if copied.Next.Next != copied {
+		t.Fatalf("expected the copy to preserve the cycle (copied.Next.Next == copied)")
+	}
+	if copied.Name == "a" || copied.Next.Name == "b" {
+		t.Fatalf("expected both nodes masked, got %q and %q", copied.Name, copied.Next.Name)
+	}
+	if a.Name != "a" || b.Name != "b" {
+		t.Fatalf("expected the original graph untouched, got %q and %q", a.Name, b.Name)
+	}
+}
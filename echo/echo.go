@@ -1,12 +1,13 @@
-package masker
+// Package echo wires masker into github.com/labstack/echo/v4.
+package echo
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/surendratiwari3/masker"
 )
 
 // JSON masks v before sending JSON response.
 func JSON(c echo.Context, code int, v interface{}) error {
-	Mask(v)
+	masker.Mask(v)
 	return c.JSON(code, v)
 }
-
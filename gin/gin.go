@@ -0,0 +1,13 @@
+// Package gin wires masker into github.com/gin-gonic/gin.
+package gin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/surendratiwari3/masker"
+)
+
+// JSON masks v before sending JSON response.
+func JSON(c *gin.Context, code int, v interface{}) {
+	masker.Mask(v)
+	c.JSON(code, v)
+}
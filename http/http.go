@@ -0,0 +1,229 @@
+// Package http is the framework-agnostic integration: a net/http middleware
+// that masks any JSON response written by the handlers it wraps, plus a
+// Transform helper for masking a JSON stream outside of the request/response
+// cycle (e.g. in a pipe between two services). Because its package name
+// shadows the standard library's, callers typically import it aliased:
+//
+//	maskerhttp "github.com/surendratiwari3/masker/http"
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/surendratiwari3/masker"
+)
+
+// Option configures Middleware.
+type Option func(*config)
+
+type config struct {
+	streamThreshold int
+}
+
+const defaultStreamThreshold = 1 << 20 // 1 MiB
+
+// WithStreamThreshold sets the response size, in bytes, above which a
+// top-level JSON array response is masked element-by-element via
+// MaskStream instead of being unmarshaled into a single []interface{}.
+// The response body itself is still fully buffered before masking -
+// Middleware must know the final Content-Length before it can write
+// headers, so it can never avoid materializing the HTTP bytes. This only
+// avoids holding the decoded representation of a large array in memory
+// all at once. Defaults to 1 MiB.
+func WithStreamThreshold(n int) Option {
+	return func(c *config) { c.streamThreshold = n }
+}
+
+type overridesContextKey struct{}
+
+// WithOverrides attaches per-request MaskOverrides (e.g. role-based
+// redaction) to ctx, for the Middleware to pick up when it masks that
+// request's response.
+func WithOverrides(ctx context.Context, overrides masker.MaskOverrides) context.Context {
+	return context.WithValue(ctx, overridesContextKey{}, overrides)
+}
+
+func overridesFromContext(ctx context.Context) masker.MaskOverrides {
+	overrides, _ := ctx.Value(overridesContextKey{}).(masker.MaskOverrides)
+	return overrides
+}
+
+// Middleware wraps next so that any response it writes with a
+// "Content-Type: application/json" header is masked before it reaches the
+// client. Masking of the decoded JSON relies on the sensitive-field
+// registry (RegisterSensitiveField et al.), since a handler's JSON output
+// carries no Go struct tags by the time it's bytes on the wire; attach
+// per-request overrides with WithOverrides if a handler needs to vary what
+// gets masked.
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	cfg := &config{streamThreshold: defaultStreamThreshold}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &responseWriter{ResponseWriter: w, cfg: cfg, overrides: overridesFromContext(r.Context())}
+			next.ServeHTTP(rw, r)
+			rw.flush()
+		})
+	}
+}
+
+// responseWriter buffers a handler's body so it can be decoded, masked and
+// re-encoded before any bytes reach the real http.ResponseWriter. Masking
+// can change Content-Length, so the status line and headers are only sent
+// once the final body is known.
+type responseWriter struct {
+	http.ResponseWriter
+	cfg       *config
+	overrides masker.MaskOverrides
+
+	status      int
+	wroteHeader bool
+	isJSON      bool
+	buf         bytes.Buffer
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.isJSON = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	w.wroteHeader = true
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.buf.Write(p)
+}
+
+// flush writes the final status, headers and body to the underlying
+// ResponseWriter. Write only ever buffers - it never reaches the real
+// ResponseWriter on its own - so flush is the one place w.status gets
+// forwarded, on every path including non-JSON bodies and empty 204/304
+// bodies, instead of leaving the real ResponseWriter to send an implicit
+// 200 on its own first Write.
+func (w *responseWriter) flush() {
+	if !w.wroteHeader {
+		return
+	}
+
+	if !w.isJSON || w.buf.Len() == 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+		if w.buf.Len() > 0 {
+			w.ResponseWriter.Write(w.buf.Bytes())
+		}
+		return
+	}
+
+	var out []byte
+	if w.buf.Len() >= w.cfg.streamThreshold && isJSONArray(w.buf.Bytes()) {
+		// The body bytes are already fully buffered above (Content-Length
+		// has to be known before headers go out) - going through
+		// MaskStream here only avoids decoding the whole array into one
+		// []interface{} in memory, element-by-element instead.
+		var streamed bytes.Buffer
+		if err := MaskStream(&streamed, bytes.NewReader(w.buf.Bytes()), w.overrides); err == nil {
+			out = streamed.Bytes()
+		}
+	}
+	if out == nil {
+		var v interface{}
+		if err := json.Unmarshal(w.buf.Bytes(), &v); err == nil {
+			if w.overrides != nil {
+				masker.MaskWithOverrides(v, w.overrides)
+			} else {
+				masker.Mask(v)
+			}
+			if encoded, err := json.Marshal(v); err == nil {
+				out = encoded
+			}
+		}
+	}
+	if out == nil {
+		out = w.buf.Bytes() // not valid JSON (or masking failed) - pass through unchanged
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(out)))
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(out)
+}
+
+func isJSONArray(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// MaskStream masks a top-level JSON array from r and writes it to w,
+// decoding and re-encoding one element at a time so the whole array never
+// needs to be held in memory as a decoded Go value at once. Whether this
+// avoids holding the raw bytes in memory too depends on r and w: Transform
+// gets the full benefit since it streams both ends, but Middleware already
+// has the whole body buffered by the time it calls this (see
+// WithStreamThreshold), so there it only reduces decode-side memory.
+// Masking is driven by the sensitive-field registry and struct tags, same
+// as Mask.
+func MaskStream(w io.Writer, r io.Reader, overrides masker.MaskOverrides) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return errors.New("masker/http: MaskStream requires a top-level JSON array")
+	}
+
+	enc := json.NewEncoder(w)
+	if _, err := w.Write([]byte{'['}); err != nil {
+		return err
+	}
+	first := true
+	for dec.More() {
+		var elem interface{}
+		if err := dec.Decode(&elem); err != nil {
+			return err
+		}
+		if !first {
+			if _, err := w.Write([]byte{','}); err != nil {
+				return err
+			}
+		}
+		first = false
+		if overrides != nil {
+			masker.MaskWithOverrides(elem, overrides)
+		} else {
+			masker.Mask(elem)
+		}
+		if err := enc.Encode(elem); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // consume closing ']'
+		return err
+	}
+	_, err = w.Write([]byte{']'})
+	return err
+}
+
+// Transform returns a reader that streams r's JSON content with masking
+// applied, for pipelines that want to pass a body through without ever
+// materializing the whole thing - e.g. proxying a paginated API response
+// while redacting PII in flight. r must be a top-level JSON array; use
+// Mask/MaskWithOverrides directly for single objects.
+func Transform(r io.Reader, overrides masker.MaskOverrides) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(MaskStream(pw, r, overrides))
+	}()
+	return pr
+}
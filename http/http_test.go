@@ -0,0 +1,70 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/surendratiwari3/masker"
+	maskerhttp "github.com/surendratiwari3/masker/http"
+)
+
+func TestMiddlewareMasksSensitiveJSONFields(t *testing.T) {
+	masker.EnableDefaultSensitiveFields()
+
+	handler := maskerhttp.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username":"alice","password":"hunter2","ssn":"123-45-6789"}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if body["username"] != "alice" {
+		t.Fatalf("expected username untouched, got %q", body["username"])
+	}
+	if body["password"] == "hunter2" {
+		t.Fatalf("expected password to be masked, got %q", body["password"])
+	}
+	if body["ssn"] == "123-45-6789" {
+		t.Fatalf("expected ssn to be masked, got %q", body["ssn"])
+	}
+}
+
+func TestMiddlewareForwardsStatusCode(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		write  bool
+	}{
+		{name: "non-JSON error page", status: http.StatusNotFound, write: true},
+		{name: "no body", status: http.StatusNoContent, write: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := maskerhttp.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(tc.status)
+				if tc.write {
+					w.Write([]byte("not found"))
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+		})
+	}
+}
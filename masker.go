@@ -1,26 +1,140 @@
 package masker
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
-// MaskFunc is a strategy function type
+// MaskFunc is a string-to-string strategy function. It's the original,
+// simplest strategy shape and still the right fit for anything that only
+// needs the field's string value (e.g. "partial", "email").
 type MaskFunc func(string) string
 
-// Global registry of masking strategies (write once at init)
+// MaskFuncV2 is a type-aware strategy function: it receives the
+// addressable reflect.Value directly and mutates it in place. Use this for
+// strategies that need the field's real type rather than its string form
+// (e.g. "zero", which must reset ints, bools and structs, not just strings).
+type MaskFuncV2 func(reflect.Value) error
+
+// Global registries of masking strategies (write once at init/registration time)
 var maskRegistry = map[string]MaskFunc{}
+var maskRegistryV2 = map[string]MaskFuncV2{}
+
+// patternMaskFunc matches strategy names that carry an argument, like
+// "filled8" or "hmac:signing-key", where exact map lookup can't work
+// because the full name isn't known ahead of time.
+type patternMaskFunc struct {
+	prefix string
+	build  func(arg string) MaskFuncV2
+}
+
+var maskPatterns []patternMaskFunc
+
+// KeyProvider resolves a named key for keyed strategies such as "hmac:<keyname>".
+// Configure one with SetKeyProvider before using those strategies.
+type KeyProvider interface {
+	Key(name string) ([]byte, bool)
+}
 
-// RegisterMaskFunc lets users register custom strategies (call during init only)
-func RegisterMaskFunc(name string, fn MaskFunc) {
-	maskRegistry[name] = fn
+var keyProvider KeyProvider
+
+// SetKeyProvider registers the KeyProvider consulted by keyed strategies
+// (currently just "hmac:<keyname>"). Call during init, before masking runs.
+func SetKeyProvider(p KeyProvider) {
+	keyProvider = p
+}
+
+// RegisterMaskFunc lets users register custom strategies (call during init
+// only). fn may be a MaskFunc (func(string) string) or a MaskFuncV2
+// (func(reflect.Value) error); any other type is ignored.
+func RegisterMaskFunc(name string, fn interface{}) {
+	switch f := fn.(type) {
+	case MaskFunc:
+		maskRegistry[name] = f
+	case func(string) string:
+		maskRegistry[name] = f
+	case MaskFuncV2:
+		maskRegistryV2[name] = f
+	case func(reflect.Value) error:
+		maskRegistryV2[name] = f
+	}
 }
 
-// MaskOverrides allows runtime override of masking rules
+// MaskOverrides allows runtime override of masking rules.
+//
+// Keys are either a bare field name ("Email"), matching that field at any
+// depth (kept for backward compatibility), or a dotted field path in the
+// style of a protobuf FieldMask ("User.Profile.Email"). A path segment of
+// "*" matches every element of a slice/array, and a map entry can be
+// targeted by its string key ("Meta.apiKey").
 type MaskOverrides map[string]string
-// key = struct field name
+
+// key = struct field name, or dotted field path
 // value = strategy name ("none" = skip masking, or any registered strategy)
 
+// pathTrie is the parsed form of the dotted-path entries in a MaskOverrides,
+// built once per Mask call and walked alongside the reflect recursion in
+// maskValue. Bare (non-dotted) keys are kept separately in a flat
+// MaskOverrides so the existing any-depth field-name matching keeps working.
+type pathTrie struct {
+	strategy    string
+	hasStrategy bool
+	children    map[string]*pathTrie
+	wildcard    *pathTrie
+}
+
+// buildPathTrie splits overrides into dotted-path entries (returned as a
+// trie) and bare field-name entries (returned as a flat MaskOverrides, for
+// the pre-existing any-depth matching behavior).
+func buildPathTrie(overrides MaskOverrides) (*pathTrie, MaskOverrides) {
+	trie := &pathTrie{children: map[string]*pathTrie{}}
+	flat := MaskOverrides{}
+	for key, strategy := range overrides {
+		if !strings.Contains(key, ".") {
+			flat[key] = strategy
+			continue
+		}
+		node := trie
+		for _, seg := range strings.Split(key, ".") {
+			if seg == "*" {
+				if node.wildcard == nil {
+					node.wildcard = &pathTrie{children: map[string]*pathTrie{}}
+				}
+				node = node.wildcard
+				continue
+			}
+			child, ok := node.children[seg]
+			if !ok {
+				child = &pathTrie{children: map[string]*pathTrie{}}
+				node.children[seg] = child
+			}
+			node = child
+		}
+		node.strategy = strategy
+		node.hasStrategy = true
+	}
+	return trie, flat
+}
+
+// child looks up the trie node for a struct field or map key, falling back
+// to the wildcard branch (the "*" segment) for map keys that weren't given
+// an explicit path.
+func (n *pathTrie) child(name string) *pathTrie {
+	if n == nil {
+		return nil
+	}
+	if c, ok := n.children[name]; ok {
+		return c
+	}
+	return nil
+}
+
 // ---------------------- Initialize default strategies ----------------------
 func init() {
 	// Basic reusable strategies
@@ -59,6 +173,63 @@ func init() {
 	maskRegistry["password"] = func(s string) string { return strings.Repeat("*", len(s)) }
 	maskRegistry["token"] = func(s string) string { return strings.Repeat("*", len(s)) }
 
+	// Deterministic / format-preserving strategies
+	maskRegistry["hash"] = func(s string) string {
+		sum := sha1.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	maskRegistry["filled"] = func(s string) string { return strings.Repeat("*", len(s)) }
+	maskRegistry["fixed"] = func(s string) string { return strings.Repeat("*", 8) }
+	maskRegistryV2["zero"] = func(v reflect.Value) error {
+		if !v.CanSet() {
+			return fmt.Errorf("masker: zero strategy requires a settable value")
+		}
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	// "filled<N>" is "filled" with a fixed asterisk count, e.g. mask:"strategy:filled5".
+	maskPatterns = append(maskPatterns, patternMaskFunc{
+		prefix: "filled",
+		build: func(arg string) MaskFuncV2 {
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil
+			}
+			return func(v reflect.Value) error {
+				if v.Kind() != reflect.String {
+					return fmt.Errorf("masker: filled<N> strategy only applies to strings")
+				}
+				v.SetString(strings.Repeat("*", n))
+				return nil
+			}
+		},
+	})
+	// "hmac:<keyname>" signs the field with HMAC-SHA256 using a key looked
+	// up from the configured KeyProvider (see SetKeyProvider).
+	maskPatterns = append(maskPatterns, patternMaskFunc{
+		prefix: "hmac:",
+		build: func(arg string) MaskFuncV2 {
+			keyName := arg
+			return func(v reflect.Value) error {
+				if v.Kind() != reflect.String {
+					return fmt.Errorf("masker: hmac strategy only applies to strings")
+				}
+				if keyProvider == nil {
+					return fmt.Errorf("masker: hmac strategy requires a KeyProvider (see SetKeyProvider)")
+				}
+				key, ok := keyProvider.Key(keyName)
+				if !ok {
+					return fmt.Errorf("masker: no hmac key registered for %q", keyName)
+				}
+				mac := hmac.New(sha256.New, key)
+				mac.Write([]byte(v.String()))
+				v.SetString(hex.EncodeToString(mac.Sum(nil)))
+				return nil
+			}
+		},
+	})
+
 	// Group strategies
 	maskRegistry["PII"] = maskRegistry["partial"]
 	maskRegistry["PHI"] = maskRegistry["dob"]
@@ -73,33 +244,117 @@ func init() {
 
 // Mask applies masking based on struct tags (default behavior)
 func Mask(v interface{}) {
-	maskValue(reflect.ValueOf(v), nil)
+	maskValue(reflect.ValueOf(v), nil, nil, map[uintptr]bool{})
 }
 
-// MaskWithOverrides applies masking with runtime overrides
+// MaskWithOverrides applies masking with runtime overrides. Overrides may be
+// bare field names or dotted field paths (see MaskOverrides).
 func MaskWithOverrides(v interface{}, overrides MaskOverrides) {
-	maskValue(reflect.ValueOf(v), overrides)
+	trie, flat := buildPathTrie(overrides)
+	maskValue(reflect.ValueOf(v), flat, trie, map[uintptr]bool{})
 }
 
-// MaskCopy returns a masked copy of the struct, keeping original safe
+// MaskCopy returns a masked copy of the struct, keeping original safe. The
+// copy is a true deep copy - nested maps, slices and pointers are all
+// allocated fresh, so masking the copy can never mutate data reachable from
+// the caller's original value.
 func MaskCopy(v interface{}, overrides MaskOverrides) interface{} {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return v
 	}
-	copyVal := reflect.New(rv.Elem().Type())
-	copyVal.Elem().Set(rv.Elem())
-	maskValue(copyVal, overrides)
+	copyVal := deepCopyValue(rv, map[uintptr]reflect.Value{})
+	trie, flat := buildPathTrie(overrides)
+	maskValue(copyVal, flat, trie, map[uintptr]bool{})
 	return copyVal.Interface()
 }
 
+// lookupPatternStrategy finds a registered pattern strategy whose prefix
+// matches name, checked only after exact-name lookup in maskRegistry and
+// maskRegistryV2 has failed.
+func lookupPatternStrategy(name string) MaskFuncV2 {
+	for _, p := range maskPatterns {
+		if !strings.HasPrefix(name, p.prefix) {
+			continue
+		}
+		if fn := p.build(strings.TrimPrefix(name, p.prefix)); fn != nil {
+			return fn
+		}
+	}
+	return nil
+}
+
+// applyStrategy resolves strategy by exact name (string strategies first,
+// then type-aware ones), then by pattern, and applies it to value. It
+// reports whether a strategy was found and successfully applied.
+func applyStrategy(value reflect.Value, strategy string) bool {
+	if fn, ok := maskRegistry[strategy]; ok {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		value.SetString(fn(value.String()))
+		return true
+	}
+	if fn, ok := maskRegistryV2[strategy]; ok {
+		return fn(value) == nil
+	}
+	if fn := lookupPatternStrategy(strategy); fn != nil {
+		return fn(value) == nil
+	}
+	return false
+}
+
+// resolveDottedOverride checks the dotted-path override at node, if any, and
+// applies its strategy to value. isNone reports that the override was the
+// literal "none" strategy, meaning value should be left untouched and no
+// further resolution (tag, sensitive-field registry) should run - shared by
+// maskValue and maskToMapValue so the two traversals can't drift apart on
+// this again.
+func resolveDottedOverride(value reflect.Value, node *pathTrie) (applied, isNone bool) {
+	if node == nil || !node.hasStrategy {
+		return false, false
+	}
+	if node.strategy == "none" {
+		return false, true
+	}
+	return applyStrategy(value, node.strategy), false
+}
+
+// resolveFlatOverride is resolveDottedOverride for a bare, any-depth
+// MaskOverrides entry keyed by fieldName.
+func resolveFlatOverride(value reflect.Value, fieldName string, overrides MaskOverrides) (applied, isNone bool) {
+	strategy, ok := overrides[fieldName]
+	if !ok {
+		return false, false
+	}
+	if strategy == "none" {
+		return false, true
+	}
+	return applyStrategy(value, strategy), false
+}
+
 // ---------------------- Internal recursive functions ----------------------
-func maskValue(rv reflect.Value, overrides MaskOverrides) {
+// maskValue walks rv applying the registered strategies. node is the
+// pathTrie position matching rv's location in the original value (nil once
+// we've walked off the end of every dotted override path), used to resolve
+// path-specific overrides alongside the flat, any-depth overrides. visited
+// remembers the addresses of pointers/slices/maps already descended into,
+// so self-referential graphs (e.g. a linked list with a cycle) terminate
+// instead of recursing forever.
+func maskValue(rv reflect.Value, overrides MaskOverrides, node *pathTrie, visited map[uintptr]bool) {
 	if !rv.IsValid() {
 		return
 	}
 
 	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		addr := rv.Pointer()
+		if visited[addr] {
+			return
+		}
+		visited[addr] = true
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Interface && !rv.IsNil() {
 		rv = rv.Elem()
 	}
 
@@ -113,45 +368,109 @@ func maskValue(rv reflect.Value, overrides MaskOverrides) {
 				continue
 			}
 			fieldName := field.Name
+			childNode := node.child(fieldName)
 
-			// ---------------- Runtime override ----------------
-			if overrides != nil {
-				if strategy, ok := overrides[fieldName]; ok {
-					if strategy == "none" {
-						continue
-					}
-					if fn, exists := maskRegistry[strategy]; exists && value.Kind() == reflect.String {
-						value.SetString(fn(value.String()))
-						continue
-					}
-				}
+			// ---------------- Overrides (dotted path, then any-depth by name) ----------------
+			applied, isNone := resolveDottedOverride(value, childNode)
+			if !applied && !isNone && overrides != nil {
+				applied, isNone = resolveFlatOverride(value, fieldName, overrides)
+			}
+			if isNone {
+				continue
+			}
+			if applied {
+				continue
 			}
 
 			// ---------------- Tag-based default ----------------
 			tag := field.Tag.Get("mask")
-			if tag != "" && value.Kind() == reflect.String {
-				parts := strings.Split(tag, ";")
-				for _, part := range parts {
+			if tag != "" {
+				for _, part := range strings.Split(tag, ";") {
 					if strings.HasPrefix(part, "strategy:") {
-						strat := strings.TrimPrefix(part, "strategy:")
-						if fn, ok := maskRegistry[strat]; ok {
-							value.SetString(fn(value.String()))
+						if applyStrategy(value, strings.TrimPrefix(part, "strategy:")) {
+							applied = true
 						}
 					}
 				}
-			} else {
-				maskValue(value, overrides)
+			}
+
+			// ---------------- Sensitive-field registry (tag-less) ----------------
+			if !applied && tag == "" {
+				if strategy, ok := lookupSensitiveField(fieldName); ok {
+					applied = applyStrategy(value, strategy)
+				}
+			}
+
+			if !applied {
+				maskValue(value, overrides, childNode, visited)
 			}
 		}
 	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && !rv.IsNil() {
+			addr := rv.Pointer()
+			if visited[addr] {
+				return
+			}
+			visited[addr] = true
+		}
+		var elemNode *pathTrie
+		if node != nil {
+			elemNode = node.wildcard
+		}
 		for i := 0; i < rv.Len(); i++ {
-			maskValue(rv.Index(i), overrides)
+			maskValue(rv.Index(i), overrides, elemNode, visited)
 		}
 	case reflect.Map:
+		if !rv.IsNil() {
+			addr := rv.Pointer()
+			if visited[addr] {
+				return
+			}
+			visited[addr] = true
+		}
 		for _, key := range rv.MapKeys() {
 			val := rv.MapIndex(key)
-			maskValue(val, overrides)
+			if !val.IsValid() {
+				continue
+			}
+			var childNode *pathTrie
+			if node != nil {
+				if key.Kind() == reflect.String {
+					childNode = node.child(key.String())
+				}
+				if childNode == nil {
+					childNode = node.wildcard
+				}
+			}
+
+			// A map[string]interface{} entry's static type is just
+			// "interface{}" - unwrap to the dynamic value it actually holds
+			// so strategy dispatch sees the real Kind (e.g. String), not
+			// Interface.
+			dyn := val
+			if dyn.Kind() == reflect.Interface && !dyn.IsNil() {
+				dyn = dyn.Elem()
+			}
+
+			// Map values from MapIndex aren't addressable, so copy into a
+			// settable temporary, mask that, then write it back.
+			tmp := reflect.New(dyn.Type()).Elem()
+			tmp.Set(dyn)
+
+			applied, isNone := resolveDottedOverride(tmp, childNode)
+			if isNone {
+				rv.SetMapIndex(key, tmp)
+				continue
+			}
+			if !applied && key.Kind() == reflect.String {
+				if strategy, ok := lookupSensitiveField(key.String()); ok {
+					applied = applyStrategy(tmp, strategy)
+				}
+			}
+			if !applied {
+				maskValue(tmp, overrides, childNode, visited)
+			}
+			rv.SetMapIndex(key, tmp)
 		}
 	}
 }
-
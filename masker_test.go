@@ -0,0 +1,14 @@
+package masker
+
+import "testing"
+
+func TestMaskMapOfAnySensitiveField(t *testing.T) {
+	EnableDefaultSensitiveFields()
+
+	m := map[string]interface{}{"ssn": "123-45-6789"}
+	Mask(m)
+
+	if m["ssn"] == "123-45-6789" {
+		t.Fatalf("expected ssn to be masked, got %q", m["ssn"])
+	}
+}
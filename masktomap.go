@@ -0,0 +1,239 @@
+package masker
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldFilter decides which fields MaskToMap includes in its output. Filter
+// is called with the exported field name of a struct being walked; it
+// reports whether that field should be included and, if so, the FieldFilter
+// to apply to its children (so filters can express per-level selections,
+// the way a protobuf FieldMask does).
+type FieldFilter interface {
+	Filter(fieldName string) (FieldFilter, bool)
+}
+
+// MaskFilter includes every field at every depth - a full projection. It's
+// the default filter when MaskToMap is called with a nil FieldFilter.
+type MaskFilter struct{}
+
+// Filter always matches, recursing with the same MaskFilter.
+func (MaskFilter) Filter(fieldName string) (FieldFilter, bool) {
+	return MaskFilter{}, true
+}
+
+// PathFilter selects fields by dotted path, the same syntax as MaskOverrides
+// ("User.Profile.Email"). Build one with NewPathFilter.
+type PathFilter struct {
+	children map[string]*PathFilter
+	selected bool
+}
+
+// NewPathFilter builds a PathFilter that includes exactly the given dotted
+// paths (and, for any path, everything beneath it).
+func NewPathFilter(paths ...string) *PathFilter {
+	root := &PathFilter{children: map[string]*PathFilter{}}
+	for _, path := range paths {
+		node := root
+		for _, seg := range strings.Split(path, ".") {
+			child, ok := node.children[seg]
+			if !ok {
+				child = &PathFilter{children: map[string]*PathFilter{}}
+				node.children[seg] = child
+			}
+			node = child
+		}
+		node.selected = true
+	}
+	return root
+}
+
+// Filter reports whether fieldName was named by one of the filter's paths,
+// returning a MaskFilter (include everything) once a selected leaf is
+// reached, or the narrower child PathFilter otherwise.
+func (p *PathFilter) Filter(fieldName string) (FieldFilter, bool) {
+	if p == nil {
+		return nil, false
+	}
+	child, ok := p.children[fieldName]
+	if !ok {
+		return nil, false
+	}
+	if child.selected && len(child.children) == 0 {
+		return MaskFilter{}, true
+	}
+	return child, true
+}
+
+// jsonFieldName resolves the map key MaskToMap should use for field,
+// honoring a `json:"name"` tag (and "-" to exclude the field, matching
+// encoding/json) so the resulting map round-trips through encoding/json.
+func jsonFieldName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+	name = strings.SplitN(tag, ",", 2)[0]
+	if name == "-" {
+		return "", true
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+// MaskToMap walks v and returns a JSON-friendly map containing only the
+// fields selected by include, masked according to overrides (dotted paths,
+// tags and the sensitive-field registry all apply, same as Mask). v and its
+// fields are never mutated or even copied wholesale - only a per-field
+// scratch value is masked before being projected into the result, so this
+// is safe to use directly on request/response structs.
+func MaskToMap(v interface{}, include FieldFilter, overrides MaskOverrides) map[string]interface{} {
+	if include == nil {
+		include = MaskFilter{}
+	}
+	trie, flat := buildPathTrie(overrides)
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	out, _ := maskToMapValue(rv, include, flat, trie).(map[string]interface{})
+	return out
+}
+
+func maskToMapValue(rv reflect.Value, filter FieldFilter, overrides MaskOverrides, node *pathTrie) interface{} {
+	if !rv.IsValid() {
+		return nil
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		rt := rv.Type()
+		out := map[string]interface{}{}
+		for i := 0; i < rv.NumField(); i++ {
+			field := rt.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			key, skip := jsonFieldName(field)
+			if skip {
+				continue
+			}
+			childFilter, ok := filter.Filter(field.Name)
+			if !ok {
+				continue
+			}
+
+			value := rv.Field(i)
+			childNode := node.child(field.Name)
+
+			// Mask into a scratch copy so the caller's struct is untouched.
+			tmp := reflect.New(value.Type()).Elem()
+			tmp.Set(value)
+
+			// "none" (dotted-path or flat override) means leave this field
+			// unmasked entirely - matching maskValue, it short-circuits
+			// before the tag/sensitive-field fallbacks ever run.
+			applied, isNone := resolveDottedOverride(tmp, childNode)
+			if !applied && !isNone {
+				applied, isNone = resolveFlatOverride(tmp, field.Name, overrides)
+			}
+			if isNone {
+				out[key] = maskToMapValue(tmp, childFilter, overrides, childNode)
+				continue
+			}
+			if !applied {
+				if tag := field.Tag.Get("mask"); tag != "" {
+					for _, part := range strings.Split(tag, ";") {
+						if strings.HasPrefix(part, "strategy:") {
+							if applyStrategy(tmp, strings.TrimPrefix(part, "strategy:")) {
+								applied = true
+							}
+						}
+					}
+				}
+			}
+			if !applied {
+				if strategy, ok := lookupSensitiveField(field.Name); ok {
+					applyStrategy(tmp, strategy)
+				}
+			}
+
+			out[key] = maskToMapValue(tmp, childFilter, overrides, childNode)
+		}
+		return out
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		var elemNode *pathTrie
+		if node != nil {
+			elemNode = node.wildcard
+		}
+		out := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			out[i] = maskToMapValue(rv.Index(i), filter, overrides, elemNode)
+		}
+		return out
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil
+		}
+		out := map[string]interface{}{}
+		for _, key := range rv.MapKeys() {
+			val := rv.MapIndex(key)
+
+			// A map[string]interface{} entry's static type is just
+			// "interface{}" - unwrap to the dynamic value it actually holds
+			// so strategy dispatch sees the real Kind (e.g. String), not
+			// Interface.
+			dyn := val
+			if dyn.Kind() == reflect.Interface && !dyn.IsNil() {
+				dyn = dyn.Elem()
+			}
+			tmp := reflect.New(dyn.Type()).Elem()
+			tmp.Set(dyn)
+
+			var childNode *pathTrie
+			if node != nil {
+				if key.Kind() == reflect.String {
+					childNode = node.child(key.String())
+				}
+				if childNode == nil {
+					childNode = node.wildcard
+				}
+			}
+
+			applied, isNone := resolveDottedOverride(tmp, childNode)
+			if isNone {
+				out[fmt.Sprint(key.Interface())] = maskToMapValue(tmp, filter, overrides, childNode)
+				continue
+			}
+			if !applied && key.Kind() == reflect.String {
+				if strategy, ok := lookupSensitiveField(key.String()); ok {
+					applyStrategy(tmp, strategy)
+				}
+			}
+
+			out[fmt.Sprint(key.Interface())] = maskToMapValue(tmp, filter, overrides, childNode)
+		}
+		return out
+
+	default:
+		return rv.Interface()
+	}
+}
@@ -0,0 +1,17 @@
+package masker
+
+import "testing"
+
+type maskToMapSubject struct {
+	SSN string `mask:"strategy:full"`
+}
+
+func TestMaskToMapNoneOverrideLeavesFieldUnmasked(t *testing.T) {
+	s := &maskToMapSubject{SSN: "123-45-6789"}
+
+	out := MaskToMap(s, nil, MaskOverrides{"SSN": "none"})
+
+	if out["SSN"] != "123-45-6789" {
+		t.Fatalf("expected SSN untouched by a \"none\" override, got %q", out["SSN"])
+	}
+}
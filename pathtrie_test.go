@@ -0,0 +1,68 @@
+package masker
+
+import "testing"
+
+type trieAddress struct {
+	City string
+}
+
+type trieProfile struct {
+	Email   string
+	Address trieAddress
+}
+
+type trieUser struct {
+	Profile trieProfile
+}
+
+type trieCard struct {
+	Number string
+}
+
+type trieOrder struct {
+	Card trieCard
+}
+
+type trieSubject struct {
+	User   trieUser
+	Orders []trieOrder
+	Meta   map[string]string
+}
+
+func TestMaskWithOverridesNestedDottedPath(t *testing.T) {
+	s := &trieSubject{User: trieUser{Profile: trieProfile{Email: "a@b.com"}}}
+
+	MaskWithOverrides(s, MaskOverrides{"User.Profile.Email": "full"})
+
+	if s.User.Profile.Email != "*******" {
+		t.Fatalf("expected Email fully masked, got %q", s.User.Profile.Email)
+	}
+}
+
+func TestMaskWithOverridesWildcardOverSlice(t *testing.T) {
+	s := &trieSubject{Orders: []trieOrder{
+		{Card: trieCard{Number: "1111222233334444"}},
+		{Card: trieCard{Number: "5555666677778888"}},
+	}}
+
+	MaskWithOverrides(s, MaskOverrides{"Orders.*.Card.Number": "full"})
+
+	for i, order := range s.Orders {
+		if order.Card.Number != "****************" {
+			t.Fatalf("order %d: expected Number fully masked, got %q", i, order.Card.Number)
+		}
+	}
+}
+
+func TestMaskWithOverridesMapKeyDottedPath(t *testing.T) {
+	s := &trieSubject{Meta: map[string]string{"apiKey": "secret", "plan": "gold"}}
+
+	MaskWithOverrides(s, MaskOverrides{"Meta.apiKey": "full"})
+
+	if s.Meta["apiKey"] != "******" {
+		t.Fatalf("expected apiKey fully masked, got %q", s.Meta["apiKey"])
+	}
+	if s.Meta["plan"] != "gold" {
+		t.Fatalf("expected plan untouched, got %q", s.Meta["plan"])
+	}
+}
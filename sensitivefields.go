@@ -0,0 +1,72 @@
+package masker
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sensitiveFields is the global registry consulted by maskValue when a
+// struct field (or string map key) has no explicit mask tag or override: if
+// its name matches here, the registered strategy applies anyway. Keys are
+// lower-cased so lookups are case-insensitive.
+var sensitiveFields = map[string]string{}
+
+// sensitiveFieldPattern pairs a compiled regexp with the strategy to apply
+// when a field or map key name matches it, for names that can't be listed
+// exhaustively (e.g. "x_api_key", "stripe_api_key").
+type sensitiveFieldPattern struct {
+	re       *regexp.Regexp
+	strategy string
+}
+
+var sensitiveFieldPatterns []sensitiveFieldPattern
+
+// RegisterSensitiveField marks any field or string map key named name
+// (case-insensitive) as sensitive, to be masked with strategy even when it
+// carries no `mask` tag. An explicit `mask` tag on a field still wins. Call
+// during init, alongside RegisterMaskFunc.
+func RegisterSensitiveField(name, strategy string) {
+	sensitiveFields[strings.ToLower(name)] = strategy
+}
+
+// RegisterSensitiveFieldPattern is RegisterSensitiveField for names that
+// share a shape rather than an exact spelling, e.g. `RegisterSensitiveFieldPattern(
+// "(?i)_api_key$", "full")`. Patterns are checked in registration order,
+// after the exact-name registry, the first time a field has no tag/override
+// match. Panics if pattern doesn't compile, same as regexp.MustCompile.
+func RegisterSensitiveFieldPattern(pattern, strategy string) {
+	sensitiveFieldPatterns = append(sensitiveFieldPatterns, sensitiveFieldPattern{
+		re:       regexp.MustCompile(pattern),
+		strategy: strategy,
+	})
+}
+
+// lookupSensitiveField resolves the strategy registered for name, if any.
+func lookupSensitiveField(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	if strategy, ok := sensitiveFields[lower]; ok {
+		return strategy, true
+	}
+	for _, p := range sensitiveFieldPatterns {
+		if p.re.MatchString(lower) {
+			return p.strategy, true
+		}
+	}
+	return "", false
+}
+
+// EnableDefaultSensitiveFields opts into a starter set of commonly sensitive
+// field names (password, ssn, api_key, authorization, email, phone,
+// credit_card), so they're masked out of the box even on structs and maps
+// the caller never annotated. Call once during init.
+func EnableDefaultSensitiveFields() {
+	RegisterSensitiveField("password", "password")
+	RegisterSensitiveField("ssn", "full")
+	RegisterSensitiveField("api_key", "full")
+	RegisterSensitiveField("apikey", "full")
+	RegisterSensitiveField("authorization", "full")
+	RegisterSensitiveField("email", "email")
+	RegisterSensitiveField("phone", "phone")
+	RegisterSensitiveField("credit_card", "creditcard")
+	RegisterSensitiveField("creditcard", "creditcard")
+}
@@ -0,0 +1,48 @@
+package masker
+
+import "testing"
+
+func TestRegisterSensitiveFieldPatternMatches(t *testing.T) {
+	RegisterSensitiveFieldPattern("(?i)apikey$", "full")
+
+	type subject struct {
+		StripeApiKey string
+	}
+	s := &subject{StripeApiKey: "sk_live_12345"}
+
+	Mask(s)
+
+	if s.StripeApiKey == "sk_live_12345" {
+		t.Fatalf("expected StripeApiKey matched by pattern to be masked, got %q", s.StripeApiKey)
+	}
+}
+
+func TestRegisterSensitiveFieldIsCaseInsensitive(t *testing.T) {
+	RegisterSensitiveField("Secret", "full")
+
+	type subject struct {
+		SECRET string
+	}
+	s := &subject{SECRET: "topsecret"}
+
+	Mask(s)
+
+	if s.SECRET == "topsecret" {
+		t.Fatalf("expected SECRET to match the \"Secret\" registration case-insensitively, got %q", s.SECRET)
+	}
+}
+
+func TestExplicitTagWinsOverSensitiveFieldRegistry(t *testing.T) {
+	RegisterSensitiveField("PinCode", "full")
+
+	type subject struct {
+		PinCode string `mask:"strategy:fixed"`
+	}
+	s := &subject{PinCode: "1234"}
+
+	Mask(s)
+
+	if s.PinCode != "********" {
+		t.Fatalf("expected the explicit tag strategy (fixed) to win over the registry, got %q", s.PinCode)
+	}
+}
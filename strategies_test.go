@@ -0,0 +1,89 @@
+package masker
+
+import (
+	"reflect"
+	"testing"
+)
+
+type strategySubject struct {
+	Age    int    `mask:"strategy:zero"`
+	Active bool   `mask:"strategy:zero"`
+	Code   string `mask:"strategy:filled5"`
+	Token  string `mask:"strategy:hmac:signing-key"`
+}
+
+func TestZeroStrategyResetsNonStringKinds(t *testing.T) {
+	s := &strategySubject{Age: 30, Active: true, Code: "skip", Token: "skip"}
+
+	Mask(s)
+
+	if s.Age != 0 {
+		t.Fatalf("expected Age reset to 0, got %d", s.Age)
+	}
+	if s.Active != false {
+		t.Fatalf("expected Active reset to false, got %v", s.Active)
+	}
+}
+
+func TestFilledNStrategyParsesCount(t *testing.T) {
+	s := &strategySubject{Code: "123456789"}
+
+	Mask(s)
+
+	if s.Code != "*****" {
+		t.Fatalf("expected Code masked to 5 asterisks, got %q", s.Code)
+	}
+}
+
+type hmacKeyProvider map[string][]byte
+
+func (p hmacKeyProvider) Key(name string) ([]byte, bool) {
+	key, ok := p[name]
+	return key, ok
+}
+
+func TestHmacStrategyUsesKeyProvider(t *testing.T) {
+	SetKeyProvider(hmacKeyProvider{"signing-key": []byte("secret")})
+	defer SetKeyProvider(nil)
+
+	a := &strategySubject{Token: "value"}
+	b := &strategySubject{Token: "value"}
+	Mask(a)
+	Mask(b)
+
+	if a.Token == "value" {
+		t.Fatalf("expected Token masked, got %q", a.Token)
+	}
+	if a.Token != b.Token {
+		t.Fatalf("expected hmac to be deterministic for the same key and input, got %q vs %q", a.Token, b.Token)
+	}
+}
+
+func TestHmacStrategyWithoutKeyProviderLeavesValueUnmasked(t *testing.T) {
+	SetKeyProvider(nil)
+
+	s := &strategySubject{Token: "value"}
+	Mask(s)
+
+	if s.Token != "value" {
+		t.Fatalf("expected Token untouched without a KeyProvider, got %q", s.Token)
+	}
+}
+
+func TestHashAndFixedStrategies(t *testing.T) {
+	apply := func(name, s string) string {
+		rv := reflect.New(reflect.TypeOf("")).Elem()
+		rv.SetString(s)
+		if !applyStrategy(rv, name) {
+			t.Fatalf("expected %q strategy to apply", name)
+		}
+		return rv.String()
+	}
+
+	if got := apply("hash", "abc"); got == "abc" {
+		t.Fatalf("expected hash strategy to change the value, got %q", got)
+	}
+	if got := apply("fixed", "x"); got != "********" {
+		t.Fatalf("expected fixed strategy to produce 8 asterisks, got %q", got)
+	}
+}